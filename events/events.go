@@ -0,0 +1,60 @@
+// Package events helps orchestrators wait for whichever of several named external
+// events arrives first, ahead of an overall deadline, by polling
+// ctx.WaitForSingleEvent's zero-timeout buffered-event check on each candidate name.
+package events
+
+import (
+	"errors"
+	"time"
+
+	"github.com/microsoft/durabletask-go/task"
+)
+
+// CancelEventName is the well-known external event name that callers raise to
+// cooperatively cancel an orchestration that's paused waiting on an event.
+const CancelEventName = "__cancel__"
+
+// ExtendTimeoutEventName is the well-known external event name that callers raise
+// to reset the deadline of the current WaitForAny call.
+const ExtendTimeoutEventName = "__extend_timeout__"
+
+// ErrTimeout is returned by WaitForAny once the overall deadline is reached without
+// any of the given event names arriving.
+var ErrTimeout = errors.New("events: timed out waiting for any named event")
+
+// pollInterval bounds how quickly WaitForAny notices an event that arrived for a
+// name other than the one it's currently blocked on. A smaller interval makes
+// cancellation more responsive at the cost of more timers in the orchestration
+// history.
+const pollInterval = 5 * time.Second
+
+// WaitForAny blocks until an external event matching one of names is received or
+// timeout elapses, whichever happens first, and unmarshals the winning event's
+// payload into output. It returns the name of the event that was received.
+func WaitForAny(ctx *task.OrchestrationContext, names []string, timeout time.Duration, output any) (string, error) {
+	deadline := ctx.CurrentTimeUtc.Add(timeout)
+
+	for {
+		for _, name := range names {
+			if err := ctx.WaitForSingleEvent(name, 0).Await(output); err == nil {
+				return name, nil
+			}
+		}
+
+		remaining := deadline.Sub(ctx.CurrentTimeUtc)
+		if remaining <= 0 {
+			return "", ErrTimeout
+		}
+
+		wait := remaining
+		if wait > pollInterval {
+			wait = pollInterval
+		}
+		if err := ctx.WaitForSingleEvent(names[0], wait).Await(output); err == nil {
+			return names[0], nil
+		}
+		// Timed out on this poll slice without seeing names[0]; loop around and
+		// re-check every name, since one of the others may have arrived and been
+		// buffered in the meantime.
+	}
+}
@@ -5,9 +5,20 @@ import (
 	"time"
 
 	"github.com/microsoft/durabletask-go/task"
+	"github.com/yuriadams/poc-durabletask/events"
 )
 
-// ExternalEventOrchestrator - Demonstrates multiple pause/resume points with external events
+// eventStep describes a single pause/resume point in ExternalEventOrchestrator: it
+// waits for eventName (or cancellation) and then runs an activity over the
+// received payload, labeling the activity input with activityStep.
+type eventStep struct {
+	eventName    string
+	activityStep string
+}
+
+// ExternalEventOrchestrator - Demonstrates multiple pause/resume points with external events.
+// At every pause it also listens for events.CancelEventName (to abandon the workflow) and
+// events.ExtendTimeoutEventName (to push the deadline back out) alongside the expected step event.
 func ExternalEventOrchestrator(ctx *task.OrchestrationContext) (any, error) {
 	fmt.Printf("\n🎯 [ORCHESTRATOR] External Event Orchestrator started\n")
 
@@ -20,53 +31,59 @@ func ExternalEventOrchestrator(ctx *task.OrchestrationContext) (any, error) {
 	}
 	fmt.Printf("✅ [ORCHESTRATOR] Step 1 COMPLETED: %s\n", step1Result)
 
-	// Step 2: Wait for step2 event
-	fmt.Printf("\n⏸️  [ORCHESTRATOR] PAUSING EXECUTION - Waiting for 'step2' event...\n")
-	fmt.Printf("📞 [ORCHESTRATOR] Timeout: 60 seconds\n")
-	fmt.Printf("💡 [HINT] Send event: curl -X POST http://localhost:8080/send-event/{instanceId} -H 'Content-Type: application/json' -d '{\"eventName\":\"step2\",\"data\":\"ok\"}'\n")
-
-	var step2Data string
-	if err := ctx.WaitForSingleEvent("step2", 60*time.Second).Await(&step2Data); err != nil {
-		fmt.Printf("⏰ [ORCHESTRATOR] TIMEOUT - No step2 event received in 60 seconds\n")
-		return "❌ Timeout: No step2 event received in 60 seconds", nil
-	}
-
-	fmt.Printf("▶️  [ORCHESTRATOR] RESUMING EXECUTION - Step2 event received: %v\n", step2Data)
-
-	// Step 3: Process step2 data
-	var step3Result string
-	fmt.Printf("🚀 [ORCHESTRATOR] Step 3: Processing step2 data...\n")
-	if err := ctx.CallActivity(ProcessingActivity, task.WithActivityInput(fmt.Sprintf("Step 3: Process %s", step2Data))).Await(&step3Result); err != nil {
-		fmt.Printf("❌ [ORCHESTRATOR] Step 3 FAILED: %v\n", err)
-		return fmt.Sprintf("❌ Step 3 failed: %v", err), nil
+	steps := []eventStep{
+		{eventName: "step2", activityStep: "Step 3: Process"},
+		{eventName: "step4", activityStep: "Step 5: Finalize"},
 	}
-	fmt.Printf("✅ [ORCHESTRATOR] Step 3 COMPLETED: %s\n", step3Result)
-
-	// Step 4: Wait for step4 event
-	fmt.Printf("\n⏸️  [ORCHESTRATOR] PAUSING EXECUTION - Waiting for 'step4' event...\n")
-	fmt.Printf("📞 [ORCHESTRATOR] Timeout: 60 seconds\n")
-	fmt.Printf("💡 [HINT] Send event: curl -X POST http://localhost:8080/send-event/{instanceId} -H 'Content-Type: application/json' -d '{\"eventName\":\"step4\",\"data\":\"done\"}'\n")
-
-	var step4Data string
-	if err := ctx.WaitForSingleEvent("step4", 60*time.Second).Await(&step4Data); err != nil {
-		fmt.Printf("⏰ [ORCHESTRATOR] TIMEOUT - No step4 event received in 60 seconds\n")
-		return "❌ Timeout: No step4 event received in 60 seconds", nil
-	}
-
-	fmt.Printf("▶️  [ORCHESTRATOR] RESUMING EXECUTION - Step4 event received: %v\n", step4Data)
 
-	// Step 5: Final processing
-	var step5Result string
-	fmt.Printf("🚀 [ORCHESTRATOR] Step 5: Final processing...\n")
-	if err := ctx.CallActivity(ProcessingActivity, task.WithActivityInput(fmt.Sprintf("Step 5: Finalize %s", step4Data))).Await(&step5Result); err != nil {
-		fmt.Printf("❌ [ORCHESTRATOR] Step 5 FAILED: %v\n", err)
-		return fmt.Sprintf("❌ Step 5 failed: %v", err), nil
+	eventData := make([]string, len(steps))
+	stepResults := make([]string, len(steps))
+
+	for i, step := range steps {
+		fmt.Printf("\n⏸️  [ORCHESTRATOR] PAUSING EXECUTION - Waiting for '%s' event...\n", step.eventName)
+		fmt.Printf("📞 [ORCHESTRATOR] Timeout: 60 seconds (resettable via /extend-timeout/{instanceId})\n")
+		fmt.Printf("💡 [HINT] Send event: curl -X POST http://localhost:8080/send-event/{instanceId} -H 'Content-Type: application/json' -d '{\"eventName\":\"%s\",\"data\":\"ok\"}'\n", step.eventName)
+		fmt.Printf("💡 [HINT] Cancel anytime: curl -X POST http://localhost:8080/cancel/{instanceId}\n")
+
+		var payload string
+		timeout := 60 * time.Second
+		var receivedEvent string
+		for {
+			eventName, err := events.WaitForAny(ctx, []string{step.eventName, events.CancelEventName, events.ExtendTimeoutEventName}, timeout, &payload)
+			if err != nil {
+				fmt.Printf("⏰ [ORCHESTRATOR] TIMEOUT - No %s event received in %s\n", step.eventName, timeout)
+				return fmt.Sprintf("❌ Timeout: No %s event received in %s", step.eventName, timeout), nil
+			}
+			if eventName == events.ExtendTimeoutEventName {
+				fmt.Printf("⏳ [ORCHESTRATOR] Deadline extended - resetting timeout to 60 seconds\n")
+				timeout = 60 * time.Second
+				continue
+			}
+			receivedEvent = eventName
+			break
+		}
+
+		if receivedEvent == events.CancelEventName {
+			fmt.Printf("🛑 [ORCHESTRATOR] CANCELED - received %s while waiting for '%s'\n", events.CancelEventName, step.eventName)
+			return fmt.Sprintf("🛑 Workflow canceled while waiting for '%s'", step.eventName), nil
+		}
+
+		fmt.Printf("▶️  [ORCHESTRATOR] RESUMING EXECUTION - %s event received: %v\n", step.eventName, payload)
+		eventData[i] = payload
+
+		var stepResult string
+		fmt.Printf("🚀 [ORCHESTRATOR] %s %s...\n", step.activityStep, payload)
+		if err := ctx.CallActivity(ProcessingActivity, task.WithActivityInput(fmt.Sprintf("%s %s", step.activityStep, payload))).Await(&stepResult); err != nil {
+			fmt.Printf("❌ [ORCHESTRATOR] %s FAILED: %v\n", step.activityStep, err)
+			return fmt.Sprintf("❌ %s failed: %v", step.activityStep, err), nil
+		}
+		fmt.Printf("✅ [ORCHESTRATOR] %s COMPLETED: %s\n", step.activityStep, stepResult)
+		stepResults[i] = stepResult
 	}
-	fmt.Printf("✅ [ORCHESTRATOR] Step 5 COMPLETED: %s\n", step5Result)
 
 	// Final result
-	finalResult := fmt.Sprintf("✅ Workflow completed! Events: [%s, %s], Results: [%s] -> [%s] -> [%s]",
-		step2Data, step4Data, step1Result, step3Result, step5Result)
+	finalResult := fmt.Sprintf("✅ Workflow completed! Events: %v, Results: [%s] -> %v",
+		eventData, step1Result, stepResults)
 
 	fmt.Printf("\n🎉 [ORCHESTRATOR] EXTERNAL EVENT WORKFLOW COMPLETED SUCCESSFULLY!\n")
 	fmt.Printf("📊 [ORCHESTRATOR] Final Result: %s\n\n", finalResult)
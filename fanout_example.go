@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/microsoft/durabletask-go/task"
+)
+
+// FanOutOrchestrator - Demonstrates sub-orchestration fan-out/fan-in: it starts N
+// instances of ChildOrchestrator in parallel, waits for all of them to complete,
+// and aggregates their results.
+func FanOutOrchestrator(ctx *task.OrchestrationContext) (any, error) {
+	var n int
+	if err := ctx.GetInput(&n); err != nil || n <= 0 {
+		n = 1
+	}
+
+	fmt.Printf("\n🎯 [ORCHESTRATOR] FanOut orchestrator started - fanning out to %d sub-orchestrations\n", n)
+
+	tasks := make([]task.Task, n)
+	for i := 0; i < n; i++ {
+		fmt.Printf("🚀 [ORCHESTRATOR] Scheduling sub-orchestration #%d...\n", i)
+		tasks[i] = ctx.CallSubOrchestrator(ChildOrchestrator, task.WithSubOrchestratorInput(fmt.Sprintf("child-%d", i)))
+	}
+
+	results := make([]string, n)
+	var failures []string
+	for i, t := range tasks {
+		var childResult string
+		if err := t.Await(&childResult); err != nil {
+			fmt.Printf("❌ [ORCHESTRATOR] Sub-orchestration #%d FAILED: %v\n", i, err)
+			failures = append(failures, fmt.Sprintf("#%d: %v", i, err))
+			continue
+		}
+		fmt.Printf("✅ [ORCHESTRATOR] Sub-orchestration #%d COMPLETED: %s\n", i, childResult)
+		results[i] = childResult
+	}
+
+	if len(failures) > 0 {
+		fmt.Printf("\n🔥 [ORCHESTRATOR] FANOUT ORCHESTRATION COMPLETED WITH FAILURES!\n")
+		return fmt.Sprintf("❌ %d/%d sub-orchestrations failed: %v", len(failures), n, failures), nil
+	}
+
+	finalResult := fmt.Sprintf("✅ Fan-out/fan-in completed! %d sub-orchestrations finished: %v", n, results)
+	fmt.Printf("\n🎉 [ORCHESTRATOR] FANOUT ORCHESTRATION COMPLETED SUCCESSFULLY!\n")
+	fmt.Printf("📊 [ORCHESTRATOR] Final Result: %s\n\n", finalResult)
+	return finalResult, nil
+}
+
+// ChildOrchestrator - Sub-orchestration invoked by FanOutOrchestrator. Runs a single
+// activity and returns its result so the parent can aggregate it.
+func ChildOrchestrator(ctx *task.OrchestrationContext) (any, error) {
+	var input string
+	if err := ctx.GetInput(&input); err != nil {
+		input = "No input"
+	}
+
+	fmt.Printf("🧩 [SUB-ORCHESTRATOR] ChildOrchestrator started: %s\n", input)
+
+	var result string
+	if err := ctx.CallActivity(ProcessingActivity, task.WithActivityInput(fmt.Sprintf("%s: child work", input))).Await(&result); err != nil {
+		fmt.Printf("❌ [SUB-ORCHESTRATOR] ChildOrchestrator FAILED: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("✅ [SUB-ORCHESTRATOR] ChildOrchestrator COMPLETED: %s\n", result)
+	return result, nil
+}
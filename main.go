@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -13,6 +14,8 @@ import (
 	"github.com/microsoft/durabletask-go/backend"
 	"github.com/microsoft/durabletask-go/backend/sqlite"
 	"github.com/microsoft/durabletask-go/task"
+	"github.com/yuriadams/poc-durabletask/events"
+	"github.com/yuriadams/poc-durabletask/supervisor"
 )
 
 var client backend.TaskHubClient
@@ -27,9 +30,14 @@ func main() {
 	r := task.NewTaskRegistry()
 	r.AddOrchestrator(ExternalEventOrchestrator)
 	r.AddOrchestrator(RetryOrchestrator)
+	r.AddOrchestrator(FanOutOrchestrator)
+	r.AddOrchestrator(ChildOrchestrator)
+	r.AddOrchestrator(SupervisorOrchestrator)
 	r.AddActivity(UnreliableActivity)
 	r.AddActivity(ReliableActivity)
 	r.AddActivity(ProcessingActivity)
+	r.AddActivity(ValidationActivity)
+	r.AddActivity(HealthProbeActivity)
 
 	// Create workers
 	ctx := context.Background()
@@ -47,16 +55,32 @@ func main() {
 	// Configure HTTP routes
 	router := mux.NewRouter()
 	router.HandleFunc("/start-external-event", startExternalEventHandler).Methods("POST")
+	router.HandleFunc("/start-external-event", startExternalEventSyncHandler).Methods("PUT")
 	router.HandleFunc("/start-retry", startRetryHandler).Methods("POST")
+	router.HandleFunc("/start-retry", startRetrySyncHandler).Methods("PUT")
+	router.HandleFunc("/start-fanout", startFanOutHandler).Methods("POST")
 	router.HandleFunc("/send-event/{instanceId}", sendEventHandler).Methods("POST")
 	router.HandleFunc("/status/{instanceId}", statusHandler).Methods("GET")
+	router.HandleFunc("/terminate/{instanceId}", terminateHandler).Methods("POST")
+	router.HandleFunc("/purge/{instanceId}", purgeHandler).Methods("POST")
+	router.HandleFunc("/cancel/{instanceId}", cancelHandler).Methods("POST")
+	router.HandleFunc("/extend-timeout/{instanceId}", extendTimeoutHandler).Methods("POST")
+	router.HandleFunc("/start-supervisor", startSupervisorHandler).Methods("POST")
 
 	fmt.Println("🚀 Server started at http://localhost:8080")
 	fmt.Println("📝 Available routes:")
 	fmt.Println("  POST /start-external-event - Start orchestrator waiting for external event")
+	fmt.Println("  PUT /start-external-event?wait=30s - Same, but block inline for completion up to wait")
 	fmt.Println("  POST /start-retry - Start orchestrator with retry policy")
+	fmt.Println("  PUT /start-retry?wait=30s - Same, but block inline for completion up to wait")
+	fmt.Println("  POST /start-fanout?n=10 - Start fan-out/fan-in sub-orchestration sample")
 	fmt.Println("  POST /send-event/{instanceId} - Send external event")
 	fmt.Println("  GET /status/{instanceId} - Query status")
+	fmt.Println("  POST /terminate/{instanceId} - Terminate a running orchestration")
+	fmt.Println("  POST /purge/{instanceId} - Purge orchestration state")
+	fmt.Println("  POST /cancel/{instanceId} - Cooperatively cancel a paused external-event orchestration")
+	fmt.Println("  POST /extend-timeout/{instanceId} - Reset the deadline of the current await point")
+	fmt.Println("  POST /start-supervisor - Start a healthcheck-style probe supervisor (Timeout can't preempt a hung probe - see doc comment)")
 
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
@@ -66,6 +90,7 @@ func startExternalEventHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("\n📥 [HTTP] POST /start-external-event - Starting external event orchestrator\n")
 
 	instanceId := api.InstanceID(fmt.Sprintf("external-event-%d", time.Now().Unix()))
+	setCorrelationId(w, instanceId)
 
 	if _, err := client.ScheduleNewOrchestration(context.Background(), ExternalEventOrchestrator, api.WithInstanceID(instanceId)); err != nil {
 		fmt.Printf("❌ [HTTP] Failed to start external event orchestrator: %v\n", err)
@@ -82,11 +107,118 @@ func startExternalEventHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// defaultSyncWait is how long a PUT .../start-* handler blocks for completion
+// when the caller doesn't supply an explicit ?wait= duration.
+const defaultSyncWait = 30 * time.Second
+
+// parseWaitParam reads the "wait" query parameter as a time.Duration (e.g.
+// "30s", "1m"), falling back to defaultSyncWait when absent.
+func parseWaitParam(r *http.Request) (time.Duration, error) {
+	raw := r.URL.Query().Get("wait")
+	if raw == "" {
+		return defaultSyncWait, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+// setCorrelationId sets the X-Correlation-Id response header to instanceId, so
+// a caller can always tie a response back to the orchestration it concerns.
+func setCorrelationId(w http.ResponseWriter, instanceId api.InstanceID) {
+	w.Header().Set("X-Correlation-Id", string(instanceId))
+}
+
+// awaitOrchestrationSync blocks up to wait for instanceId to reach a terminal
+// state and writes the outcome to w: the final metadata as JSON on completion,
+// or 202 Accepted with a Location header pointing at /status/{instanceId} if
+// wait elapses first, so the caller can fall back to polling.
+func awaitOrchestrationSync(w http.ResponseWriter, instanceId api.InstanceID, wait time.Duration) {
+	setCorrelationId(w, instanceId)
+
+	waitCtx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+
+	metadata, err := client.WaitForOrchestrationCompletion(waitCtx, instanceId)
+	if err != nil {
+		if waitCtx.Err() != nil {
+			fmt.Printf("⏳ [HTTP] Wait budget of %s elapsed before %s completed - falling back to polling\n", wait, instanceId)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Location", fmt.Sprintf("/status/%s", instanceId))
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]string{
+				"instanceId": string(instanceId),
+				"message":    fmt.Sprintf("Orchestration still running after %s; poll Location for status", wait),
+			})
+			return
+		}
+
+		fmt.Printf("❌ [HTTP] Failed waiting for %s to complete: %v\n", instanceId, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] %s reached terminal state %s\n", instanceId, metadata.RuntimeStatus.String())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instanceId":    string(instanceId),
+		"name":          metadata.Name,
+		"runtimeStatus": metadata.RuntimeStatus.String(),
+		"output":        metadata.SerializedOutput,
+	})
+}
+
+// Handler to start the external-event orchestrator and block inline for its result
+func startExternalEventSyncHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Printf("\n📥 [HTTP] PUT /start-external-event - Starting external event orchestrator and waiting for completion\n")
+
+	wait, err := parseWaitParam(r)
+	if err != nil {
+		http.Error(w, "Invalid 'wait' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	instanceId := api.InstanceID(fmt.Sprintf("external-event-%d", time.Now().Unix()))
+	setCorrelationId(w, instanceId)
+
+	if _, err := client.ScheduleNewOrchestration(context.Background(), ExternalEventOrchestrator, api.WithInstanceID(instanceId)); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to start external event orchestrator: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] External event orchestrator started with ID: %s, waiting up to %s\n", instanceId, wait)
+	awaitOrchestrationSync(w, instanceId, wait)
+}
+
+// Handler to start orchestrator with retry policy and block inline for its result
+func startRetrySyncHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Printf("\n📥 [HTTP] PUT /start-retry - Starting retry orchestrator and waiting for completion\n")
+
+	wait, err := parseWaitParam(r)
+	if err != nil {
+		http.Error(w, "Invalid 'wait' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	instanceId := api.InstanceID(fmt.Sprintf("retry-%d", time.Now().Unix()))
+	setCorrelationId(w, instanceId)
+
+	if _, err := client.ScheduleNewOrchestration(context.Background(), RetryOrchestrator, api.WithInstanceID(instanceId)); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to start retry orchestrator: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] Retry orchestrator started with ID: %s, waiting up to %s\n", instanceId, wait)
+	awaitOrchestrationSync(w, instanceId, wait)
+}
+
 // Handler to start orchestrator with retry policy
 func startRetryHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("\n📥 [HTTP] POST /start-retry - Starting retry orchestrator\n")
 
 	instanceId := api.InstanceID(fmt.Sprintf("retry-%d", time.Now().Unix()))
+	setCorrelationId(w, instanceId)
 
 	if _, err := client.ScheduleNewOrchestration(context.Background(), RetryOrchestrator, api.WithInstanceID(instanceId)); err != nil {
 		fmt.Printf("❌ [HTTP] Failed to start retry orchestrator: %v\n", err)
@@ -103,10 +235,221 @@ func startRetryHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Handler to start the fan-out/fan-in sub-orchestration sample
+func startFanOutHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Printf("\n📥 [HTTP] POST /start-fanout - Starting fan-out orchestrator\n")
+
+	n := 10
+	if raw := r.URL.Query().Get("n"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid 'n' query parameter", http.StatusBadRequest)
+			return
+		}
+		n = parsed
+	}
+
+	instanceId := api.InstanceID(fmt.Sprintf("fanout-%d", time.Now().Unix()))
+	setCorrelationId(w, instanceId)
+
+	if _, err := client.ScheduleNewOrchestration(context.Background(), FanOutOrchestrator, api.WithInstanceID(instanceId), api.WithInput(n)); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to start fanout orchestrator: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] FanOut orchestrator started with ID: %s (n=%d)\n", instanceId, n)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instanceId": string(instanceId),
+		"n":          n,
+		"message":    "FanOut orchestrator started",
+	})
+}
+
+// Handler to terminate a running orchestration
+func terminateHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceId := api.InstanceID(vars["instanceId"])
+	setCorrelationId(w, instanceId)
+
+	fmt.Printf("\n📥 [HTTP] POST /terminate/%s - Terminating orchestration\n", instanceId)
+
+	if err := client.TerminateOrchestration(context.Background(), instanceId, api.WithOutput("Terminated via HTTP API")); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to terminate orchestration: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] Orchestration %s terminated\n", instanceId)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"instanceId": string(instanceId),
+		"message":    "Orchestration terminated",
+	})
+}
+
+// Handler to purge orchestration state
+func purgeHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceId := api.InstanceID(vars["instanceId"])
+	setCorrelationId(w, instanceId)
+
+	fmt.Printf("\n📥 [HTTP] POST /purge/%s - Purging orchestration state\n", instanceId)
+
+	if err := client.PurgeOrchestrationState(context.Background(), instanceId); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to purge orchestration state: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] Orchestration %s purged\n", instanceId)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"instanceId": string(instanceId),
+		"message":    "Orchestration state purged",
+	})
+}
+
+// Handler to cooperatively cancel an orchestration paused on an external event
+func cancelHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceId := api.InstanceID(vars["instanceId"])
+	setCorrelationId(w, instanceId)
+
+	fmt.Printf("\n📥 [HTTP] POST /cancel/%s - Raising %s event\n", instanceId, events.CancelEventName)
+
+	if err := client.RaiseEvent(context.Background(), instanceId, events.CancelEventName); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to raise cancel event: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] Cancel event raised for %s\n", instanceId)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"instanceId": string(instanceId),
+		"message":    "Cancel event raised",
+	})
+}
+
+// Handler to reset the deadline of the await point an orchestration is paused on
+func extendTimeoutHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceId := api.InstanceID(vars["instanceId"])
+	setCorrelationId(w, instanceId)
+
+	fmt.Printf("\n📥 [HTTP] POST /extend-timeout/%s - Raising %s event\n", instanceId, events.ExtendTimeoutEventName)
+
+	if err := client.RaiseEvent(context.Background(), instanceId, events.ExtendTimeoutEventName); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to raise extend-timeout event: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] Extend-timeout event raised for %s\n", instanceId)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"instanceId": string(instanceId),
+		"message":    "Extend-timeout event raised",
+	})
+}
+
+// Handler to start the healthcheck-style probe supervisor.
+//
+// Known limitation: Timeout is NOT preemptive. durabletask-go has no way to
+// cancel an in-flight activity task, so SupervisorOrchestrator can only compare
+// elapsed time against Timeout after HealthProbeActivity returns - if the probe
+// hangs instead of erroring, the orchestration blocks on it forever and Timeout
+// never fires. Only use this supervisor to catch probes that fail fast; a probe
+// that can hang needs its own internal deadline (e.g. an http.Client timeout).
+func startSupervisorHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Printf("\n📥 [HTTP] POST /start-supervisor - Starting supervisor orchestrator\n")
+
+	var request struct {
+		Interval float64 `json:"interval"`
+		Timeout  float64 `json:"timeout"`
+		Retries  int     `json:"retries"`
+		Restart  string  `json:"restart"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Printf("❌ [HTTP] Invalid JSON in request body: %v\n", err)
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	restartPolicy, err := parseRestartPolicy(request.Restart)
+	if err != nil {
+		fmt.Printf("❌ [HTTP] Invalid restart policy %q: %v\n", request.Restart, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := supervisor.State{
+		Options: supervisor.Options{
+			Interval:      time.Duration(request.Interval * float64(time.Second)),
+			Timeout:       time.Duration(request.Timeout * float64(time.Second)),
+			Retries:       request.Retries,
+			RestartPolicy: restartPolicy,
+		},
+	}
+	if state.Options.Interval <= 0 {
+		state.Options.Interval = 30 * time.Second
+	}
+	if state.Options.Timeout <= 0 {
+		state.Options.Timeout = 10 * time.Second
+	}
+
+	instanceId := api.InstanceID(fmt.Sprintf("supervisor-%d", time.Now().Unix()))
+	setCorrelationId(w, instanceId)
+
+	if _, err := client.ScheduleNewOrchestration(context.Background(), SupervisorOrchestrator, api.WithInstanceID(instanceId), api.WithInput(state)); err != nil {
+		fmt.Printf("❌ [HTTP] Failed to start supervisor orchestrator: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("✅ [HTTP] Supervisor orchestrator started with ID: %s (interval=%s, timeout=%s, retries=%d, restart=%s)\n",
+		instanceId, state.Options.Interval, state.Options.Timeout, state.Options.Retries, state.Options.RestartPolicy)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instanceId": string(instanceId),
+		"interval":   state.Options.Interval.String(),
+		"timeout":    state.Options.Timeout.String(),
+		"retries":    state.Options.Retries,
+		"restart":    state.Options.RestartPolicy.String(),
+		"message":    "Supervisor orchestrator started",
+	})
+}
+
+// parseRestartPolicy maps the docker-style restart names accepted over HTTP to a
+// supervisor.RestartPolicy. An empty string defaults to RestartOnAny, the usual
+// choice for an ongoing healthcheck.
+func parseRestartPolicy(raw string) (supervisor.RestartPolicy, error) {
+	switch raw {
+	case "", "always", "any":
+		return supervisor.RestartOnAny, nil
+	case "on-failure":
+		return supervisor.RestartOnFailure, nil
+	case "none", "no":
+		return supervisor.RestartOnNone, nil
+	default:
+		return 0, fmt.Errorf("unknown restart policy %q (expected one of: always, on-failure, none)", raw)
+	}
+}
+
 // Handler to send external event
 func sendEventHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	instanceId := api.InstanceID(vars["instanceId"])
+	setCorrelationId(w, instanceId)
 
 	fmt.Printf("\n📥 [HTTP] POST /send-event/%s - Sending external event\n", instanceId)
 
@@ -141,6 +484,7 @@ func sendEventHandler(w http.ResponseWriter, r *http.Request) {
 func statusHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	instanceId := api.InstanceID(vars["instanceId"])
+	setCorrelationId(w, instanceId)
 
 	fmt.Printf("\n📥 [HTTP] GET /status/%s - Querying orchestration status\n", instanceId)
 
@@ -153,10 +497,35 @@ func statusHandler(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Printf("📊 [HTTP] Status for %s: %s\n", instanceId, metadata.RuntimeStatus.String())
 
+	subOrchestrationIds := findSubOrchestrationInstanceIds(instanceId)
+	if len(subOrchestrationIds) > 0 {
+		fmt.Printf("🧩 [HTTP] Found %d sub-orchestration instance(s) for %s\n", len(subOrchestrationIds), instanceId)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"instanceId":    string(instanceId),
-		"name":          metadata.Name,
-		"runtimeStatus": metadata.RuntimeStatus.String(),
+		"instanceId":                  string(instanceId),
+		"name":                        metadata.Name,
+		"runtimeStatus":               metadata.RuntimeStatus.String(),
+		"subOrchestrationInstanceIds": subOrchestrationIds,
 	})
 }
+
+// findSubOrchestrationInstanceIds walks the known child instance IDs of a parent
+// orchestration. When a caller doesn't supply an explicit sub-orchestration
+// instance ID, the durabletask-go runtime auto-generates one deterministically as
+// "<parentInstanceId>:<taskId>" (taskId being the zero-based, hex-encoded sequence
+// number of the CallSubOrchestrator action), so we probe that sequence until we hit
+// a task ID that was never scheduled. This assumes every action scheduled by the
+// parent is a sub-orchestration call, which holds for FanOutOrchestrator.
+func findSubOrchestrationInstanceIds(parentInstanceId api.InstanceID) []string {
+	var childIds []string
+	for taskID := 0; ; taskID++ {
+		childId := api.InstanceID(fmt.Sprintf("%s:%04x", parentInstanceId, taskID))
+		if _, err := client.FetchOrchestrationMetadata(context.Background(), childId); err != nil {
+			break
+		}
+		childIds = append(childIds, string(childId))
+	}
+	return childIds
+}
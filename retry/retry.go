@@ -0,0 +1,157 @@
+// Package retry implements activity retries with jittered exponential backoff, a
+// cumulative retry-timeout budget, and sentinel-error classification.
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/microsoft/durabletask-go/task"
+)
+
+// Jitter controls how randomness is applied to the computed backoff delay.
+type Jitter int
+
+const (
+	// NoJitter uses the computed backoff delay as-is.
+	NoJitter Jitter = iota
+	// FullJitter picks a delay uniformly at random between 0 and the computed
+	// backoff delay: sleep = random_between(0, min(max, initial*coeff^attempt)).
+	FullJitter
+	// EqualJitter picks a delay uniformly at random between half and the full
+	// computed backoff delay, so the wait never drops all the way to zero.
+	EqualJitter
+)
+
+// ErrRetryable marks an activity error as transient. Activities can wrap a transient
+// failure with it, e.g. fmt.Errorf("connection reset: %w", retry.ErrRetryable).
+var ErrRetryable = errors.New("retry: transient error")
+
+// ErrNonRetryable marks an activity error as permanent. CallActivity stops retrying
+// as soon as an error wraps this sentinel, e.g. on validation-style failures.
+var ErrNonRetryable = errors.New("retry: non-retryable error")
+
+// IsRetryable classifies an activity error returned from Await. Errors wrapping
+// ErrNonRetryable are never retried; everything else - including errors wrapping
+// ErrRetryable and plain, unclassified errors - is treated as retryable.
+func IsRetryable(err error) bool {
+	return !errors.Is(err, ErrNonRetryable)
+}
+
+// Policy configures jittered exponential backoff for CallActivity.
+type Policy struct {
+	// MaxAttempts is the max number of calls to make, first execution inclusive.
+	// Zero or negative means retry indefinitely until RetryTimeout is exceeded.
+	MaxAttempts int
+	// InitialRetryInterval is the backoff delay used for the first retry.
+	InitialRetryInterval time.Duration
+	// MaxRetryInterval caps the computed backoff delay before jitter is applied.
+	MaxRetryInterval time.Duration
+	// BackoffCoefficient controls the rate of increase of the backoff delay.
+	BackoffCoefficient float64
+	// RetryTimeout bounds the cumulative elapsed time across all attempts. Zero
+	// means no budget - only MaxAttempts bounds the loop.
+	RetryTimeout time.Duration
+	// Jitter selects how randomness is applied to the computed backoff delay.
+	Jitter Jitter
+}
+
+// NewRetryPolicy translates policy into a *task.RetryPolicy whose Handle hook is
+// IsRetryable, for callers who want durabletask-go's own built-in retry mechanism
+// (ctx.CallActivity + task.WithActivityRetryPolicy) rather than CallActivity's
+// jittered timer loop below. Note that the built-in mechanism has no jitter hook,
+// so backoff under it is the library's plain exponential delay.
+func (p Policy) NewRetryPolicy() *task.RetryPolicy {
+	return &task.RetryPolicy{
+		MaxAttempts:          p.MaxAttempts,
+		InitialRetryInterval: p.InitialRetryInterval,
+		BackoffCoefficient:   p.BackoffCoefficient,
+		MaxRetryInterval:     p.MaxRetryInterval,
+		RetryTimeout:         p.RetryTimeout,
+		Handle:               IsRetryable,
+	}
+}
+
+// CallActivityWithHandler is a thin wrapper around ctx.CallActivity that relies on
+// the built-in retry mechanism instead of CallActivity's timer loop, consulting
+// IsRetryable via policy.NewRetryPolicy's Handle hook before each retry.
+func CallActivityWithHandler(ctx *task.OrchestrationContext, activity interface{}, input any, policy Policy) task.Task {
+	if input != nil {
+		return ctx.CallActivity(activity, task.WithActivityInput(input), task.WithActivityRetryPolicy(policy.NewRetryPolicy()))
+	}
+	return ctx.CallActivity(activity, task.WithActivityRetryPolicy(policy.NewRetryPolicy()))
+}
+
+// CallActivity invokes activity with the given input (pass nil for activities that
+// take no input) and retries it according to policy, sleeping between attempts on a
+// durable timer so the backoff survives process restarts and replays. It stops
+// retrying as soon as IsRetryable reports false for the returned error, once
+// MaxAttempts is reached, or once the cumulative elapsed time exceeds RetryTimeout.
+func CallActivity(ctx *task.OrchestrationContext, activity interface{}, input any, output any, policy Policy) error {
+	start := ctx.CurrentTimeUtc
+	var lastErr error
+
+	for attempt := 0; policy.MaxAttempts <= 0 || attempt < policy.MaxAttempts; attempt++ {
+		var activityTask task.Task
+		if input != nil {
+			activityTask = ctx.CallActivity(activity, task.WithActivityInput(input))
+		} else {
+			activityTask = ctx.CallActivity(activity)
+		}
+
+		err := activityTask.Await(output)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !IsRetryable(err) {
+			return err
+		}
+		if policy.MaxAttempts > 0 && attempt+1 >= policy.MaxAttempts {
+			break
+		}
+		if policy.RetryTimeout > 0 && ctx.CurrentTimeUtc.Sub(start) >= policy.RetryTimeout {
+			break
+		}
+
+		if err := ctx.CreateTimer(policy.nextDelay(ctx, attempt)).Await(nil); err != nil {
+			return err
+		}
+	}
+
+	return lastErr
+}
+
+// nextDelay computes the exponential backoff delay for the given (zero-based)
+// attempt and applies jitter. The jitter source is seeded deterministically from
+// the orchestration's replay-stable clock, instance ID and attempt number rather
+// than math/rand's global source, so replaying the orchestration from history
+// reschedules the exact same timer instead of drifting to a new delay.
+func (p Policy) nextDelay(ctx *task.OrchestrationContext, attempt int) time.Duration {
+	backoff := float64(p.InitialRetryInterval) * math.Pow(p.BackoffCoefficient, float64(attempt))
+	if max := float64(p.MaxRetryInterval); max > 0 && backoff > max {
+		backoff = max
+	}
+	capped := int64(backoff)
+
+	switch p.Jitter {
+	case FullJitter:
+		return time.Duration(deterministicRand(ctx, attempt).Int63n(capped + 1))
+	case EqualJitter:
+		half := capped / 2
+		return time.Duration(half + deterministicRand(ctx, attempt).Int63n(half+1))
+	default:
+		return time.Duration(capped)
+	}
+}
+
+func deterministicRand(ctx *task.OrchestrationContext, attempt int) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%d", ctx.ID, ctx.CurrentTimeUtc.UnixNano(), attempt)
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
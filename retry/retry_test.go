@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/microsoft/durabletask-go/api"
+	"github.com/microsoft/durabletask-go/task"
+)
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"plain error", errors.New("boom"), true},
+		{"wraps ErrRetryable", fmt.Errorf("connection reset: %w", ErrRetryable), true},
+		{"wraps ErrNonRetryable", fmt.Errorf("invalid input: %w", ErrNonRetryable), false},
+		{"ErrNonRetryable itself", ErrNonRetryable, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsRetryable(c.err); got != c.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+// testOrchestrationContext returns an OrchestrationContext suitable for calling
+// unexported helpers like nextDelay in tests, with CurrentTimeUtc pinned so
+// successive calls within a test observe a stable replay-clock value.
+func testOrchestrationContext(instanceID string) *task.OrchestrationContext {
+	ctx := task.NewOrchestrationContext(task.NewTaskRegistry(), api.InstanceID(instanceID), nil, nil)
+	ctx.CurrentTimeUtc = time.Unix(0, 0).UTC()
+	return ctx
+}
+
+func TestPolicyNextDelay_RespectsJitterBounds(t *testing.T) {
+	policy := Policy{
+		InitialRetryInterval: 100 * time.Millisecond,
+		MaxRetryInterval:     5 * time.Second,
+		BackoffCoefficient:   2.0,
+	}
+
+	ctx := testOrchestrationContext("jitter-bounds")
+
+	for _, jitter := range []Jitter{NoJitter, FullJitter, EqualJitter} {
+		policy.Jitter = jitter
+		for attempt := 0; attempt < 1000; attempt++ {
+			backoff := float64(policy.InitialRetryInterval) * pow(policy.BackoffCoefficient, attempt)
+			if backoff > float64(policy.MaxRetryInterval) {
+				backoff = float64(policy.MaxRetryInterval)
+			}
+
+			min := time.Duration(0)
+			if jitter == EqualJitter {
+				min = time.Duration(int64(backoff)) / 2
+			}
+			max := time.Duration(int64(backoff))
+
+			delay := policy.nextDelay(ctx, attempt)
+			if delay < min || delay > max {
+				t.Fatalf("jitter=%d attempt=%d: delay %s out of bounds [%s, %s]", jitter, attempt, delay, min, max)
+			}
+		}
+	}
+}
+
+func pow(base float64, exp int) float64 {
+	result := 1.0
+	for i := 0; i < exp; i++ {
+		result *= base
+	}
+	return result
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/microsoft/durabletask-go/task"
+	"github.com/yuriadams/poc-durabletask/retry"
 )
 
 // Global counter to simulate deterministic attempts
@@ -21,20 +22,27 @@ func RetryOrchestrator(ctx *task.OrchestrationContext) (any, error) {
 	fmt.Printf("\n🎯 [ORCHESTRATOR] Retry orchestrator started/restarted\n")
 	fmt.Printf("📋 [ORCHESTRATOR] This orchestrator demonstrates that completed activities are NOT re-executed\n")
 
-	// Configure retry policy
-	retryPolicy := &task.RetryPolicy{
+	// Configure jittered retry policy - see the retry package for how jitter,
+	// the RetryTimeout budget and error classification interact.
+	retryPolicy := retry.Policy{
 		MaxAttempts:          5,
 		InitialRetryInterval: 1 * time.Second,
 		MaxRetryInterval:     10 * time.Second,
 		BackoffCoefficient:   2.0,
+		RetryTimeout:         30 * time.Second,
+		Jitter:               retry.FullJitter,
 	}
 
-	fmt.Printf("⚙️  [ORCHESTRATOR] Retry Policy: MaxAttempts=%d, InitialInterval=1s, BackoffCoeff=2.0\n", retryPolicy.MaxAttempts)
+	fmt.Printf("⚙️  [ORCHESTRATOR] Retry Policy: MaxAttempts=%d, InitialInterval=1s, BackoffCoeff=2.0, RetryTimeout=%s, Jitter=FullJitter\n", retryPolicy.MaxAttempts, retryPolicy.RetryTimeout)
 
-	// Activity 1: Always succeeds (to show it doesn't re-execute)
+	// Activity 1: Always succeeds (to show it doesn't re-execute). Routed through
+	// CallActivityWithHandler to exercise durabletask-go's own built-in retry
+	// mechanism (ctx.CallActivity + task.WithActivityRetryPolicy), classified by
+	// retryPolicy.NewRetryPolicy's Handle hook, as an alternative to the
+	// retry.CallActivity timer-loop path Activity 3 uses below.
 	var result1 string
-	fmt.Printf("\n🚀 [ORCHESTRATOR] Calling Activity 1 (ReliableActivity - Always succeeds)...\n")
-	if err := ctx.CallActivity(ReliableActivity, task.WithActivityInput("Step 1: Initialize")).Await(&result1); err != nil {
+	fmt.Printf("\n🚀 [ORCHESTRATOR] Calling Activity 1 (ReliableActivity via CallActivityWithHandler - Always succeeds)...\n")
+	if err := retry.CallActivityWithHandler(ctx, ReliableActivity, "Step 1: Initialize", retryPolicy).Await(&result1); err != nil {
 		fmt.Printf("❌ [ORCHESTRATOR] Activity 1 FAILED: %v\n", err)
 		return fmt.Sprintf("❌ Activity 1 failed: %v", err), nil
 	}
@@ -49,11 +57,19 @@ func RetryOrchestrator(ctx *task.OrchestrationContext) (any, error) {
 	}
 	fmt.Printf("✅ [ORCHESTRATOR] Activity 2 COMPLETED: %s\n", result2)
 
-	// Activity 3: Fails a few times then succeeds (with retry policy)
+	// Activity 2.5: Demonstrates that a non-retryable error short-circuits the
+	// retry loop instead of exhausting MaxAttempts.
+	fmt.Printf("\n🚀 [ORCHESTRATOR] Calling ValidationActivity (always fails with a non-retryable error)...\n")
+	var validationResult string
+	if err := retry.CallActivity(ctx, ValidationActivity, "Step 2.5: Validate input", &validationResult, retryPolicy); err != nil {
+		fmt.Printf("⛔ [ORCHESTRATOR] ValidationActivity FAILED without retrying (non-retryable): %v\n", err)
+	}
+
+	// Activity 3: Fails a few times then succeeds (with jittered retry policy)
 	fmt.Printf("\n🚀 [ORCHESTRATOR] Calling Activity 3 (UnreliableActivity - Will fail and trigger retries)...\n")
-	fmt.Printf("🔄 [ORCHESTRATOR] Activity 3 has retry policy enabled - will auto-retry on failures\n")
+	fmt.Printf("🔄 [ORCHESTRATOR] Activity 3 uses retry.CallActivity - will auto-retry on failures with jittered backoff\n")
 	var result3 string
-	if err := ctx.CallActivity(UnreliableActivity, task.WithActivityRetryPolicy(retryPolicy)).Await(&result3); err != nil {
+	if err := retry.CallActivity(ctx, UnreliableActivity, nil, &result3, retryPolicy); err != nil {
 		// Reset counter after complete failure
 		counterMutex.Lock()
 		attemptCounter = 0
@@ -137,13 +153,9 @@ func UnreliableActivity(ctx task.ActivityContext) (any, error) {
 
 		errorMsg := errorTypes[currentAttempt]
 		fmt.Printf("❌ [ACTIVITY] UnreliableActivity - Attempt #%d FAILED: %s\n", currentAttempt, errorMsg)
-
-		// Calculate wait time for next attempt (exponential backoff)
-		waitTime := 1 << (currentAttempt - 1) // 1s, 2s, 4s
-		fmt.Printf("⏳ [FRAMEWORK] Framework will retry in %ds...\n", waitTime)
 		fmt.Printf("💡 [NOTE] Activities 1 & 2 will NOT re-execute on retry - only failed activity retries!\n")
 
-		return nil, fmt.Errorf(errorMsg)
+		return nil, fmt.Errorf("%s: %w", errorMsg, retry.ErrRetryable)
 	}
 
 	// Success on 4th attempt or later
@@ -154,3 +166,17 @@ func UnreliableActivity(ctx task.ActivityContext) (any, error) {
 
 	return successMsg, nil
 }
+
+// ValidationActivity - Simulates a validation-style failure that should never be
+// retried, to demonstrate retry.ErrNonRetryable short-circuiting the retry loop.
+func ValidationActivity(ctx task.ActivityContext) (any, error) {
+	var input string
+	if err := ctx.GetInput(&input); err != nil {
+		input = "No input"
+	}
+
+	fmt.Printf("🔄 [ACTIVITY] ValidationActivity STARTED: %s\n", input)
+	fmt.Printf("❌ [ACTIVITY] ValidationActivity FAILED: invalid input (non-retryable)\n")
+
+	return nil, fmt.Errorf("invalid input %q: %w", input, retry.ErrNonRetryable)
+}
@@ -0,0 +1,71 @@
+// Package supervisor holds the data types and restart-decision logic behind
+// SupervisorOrchestrator: a probe that runs on a fixed Interval, tolerates up to
+// Retries consecutive failures, and whose RestartPolicy decides - the same way a
+// container runtime's restart policy does - whether a probe outcome should keep
+// the supervisor running (restart) or let it stop for good.
+package supervisor
+
+import "time"
+
+// RestartPolicy decides whether the supervisor continues after a probe outcome,
+// mirroring the restart-policy vocabulary used by container orchestrators.
+type RestartPolicy int
+
+const (
+	// RestartOnNone stops the supervisor after the very first probe, regardless
+	// of whether it succeeded or failed.
+	RestartOnNone RestartPolicy = iota
+	// RestartOnFailure keeps the supervisor running only while probes keep
+	// failing; it stops as soon as a probe succeeds.
+	RestartOnFailure
+	// RestartOnAny keeps the supervisor running after every probe outcome. This
+	// is the usual choice for an ongoing healthcheck.
+	RestartOnAny
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartOnFailure:
+		return "RestartOnFailure"
+	case RestartOnAny:
+		return "RestartOnAny"
+	default:
+		return "RestartOnNone"
+	}
+}
+
+// ShouldRestart reports whether the supervisor should continue (ContinueAsNew)
+// given the outcome of the most recent probe and the configured RestartPolicy.
+func ShouldRestart(policy RestartPolicy, probeSucceeded bool) bool {
+	switch policy {
+	case RestartOnAny:
+		return true
+	case RestartOnFailure:
+		return !probeSucceeded
+	default:
+		return false
+	}
+}
+
+// Options configures a supervised probe loop.
+type Options struct {
+	// Interval is the fixed delay between the start of one probe and the next.
+	Interval time.Duration `json:"interval"`
+	// Timeout bounds how long a single probe call is allowed to take. It is
+	// enforced only after the probe returns, not preemptively, so it cannot
+	// catch a probe that hangs rather than erroring out.
+	Timeout time.Duration `json:"timeout"`
+	// Retries is the number of consecutive probe failures tolerated before the
+	// supervisor gives up entirely, regardless of RestartPolicy.
+	Retries int `json:"retries"`
+	// RestartPolicy decides whether the supervisor continues after each probe.
+	RestartPolicy RestartPolicy `json:"restart"`
+}
+
+// State is the ContinueAsNew payload SupervisorOrchestrator carries from one
+// generation to the next, so the consecutive-failure count survives restarts
+// instead of resetting every time the history is truncated.
+type State struct {
+	Options             Options `json:"options"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+}
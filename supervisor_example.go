@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/microsoft/durabletask-go/task"
+	"github.com/yuriadams/poc-durabletask/supervisor"
+)
+
+// SupervisorOrchestrator - Runs HealthProbeActivity on a fixed interval, forever,
+// borrowing the healthcheck/restart-policy pattern from container orchestrators.
+// Timeout is enforced only after the probe returns (see supervisor.Options.Timeout),
+// so a slow-but-successful probe still counts as a failure.
+func SupervisorOrchestrator(ctx *task.OrchestrationContext) (any, error) {
+	var state supervisor.State
+	if err := ctx.GetInput(&state); err != nil {
+		return nil, fmt.Errorf("invalid supervisor input: %w", err)
+	}
+
+	fmt.Printf("\n🎯 [ORCHESTRATOR] Supervisor tick started (consecutiveFailures=%d, restart=%s)\n",
+		state.ConsecutiveFailures, state.Options.RestartPolicy)
+
+	// Invariant: the interval timer is created before the probe call so that a
+	// slow probe doesn't skew the schedule - the next tick always starts
+	// Interval after this one started, not Interval after the probe finished.
+	intervalTimer := ctx.CreateTimer(state.Options.Interval)
+
+	probeStarted := ctx.CurrentTimeUtc
+	var probeResult string
+	probeErr := ctx.CallActivity(HealthProbeActivity).Await(&probeResult)
+	if probeErr == nil && ctx.CurrentTimeUtc.Sub(probeStarted) > state.Options.Timeout {
+		probeErr = fmt.Errorf("probe exceeded timeout of %s", state.Options.Timeout)
+	}
+	probeSucceeded := probeErr == nil
+
+	if probeSucceeded {
+		state.ConsecutiveFailures = 0
+		fmt.Printf("✅ [ORCHESTRATOR] Probe healthy: %s\n", probeResult)
+	} else {
+		state.ConsecutiveFailures++
+		fmt.Printf("❌ [ORCHESTRATOR] Probe failed (%d consecutive): %v\n", state.ConsecutiveFailures, probeErr)
+	}
+
+	if err := intervalTimer.Await(nil); err != nil {
+		return nil, err
+	}
+
+	if !probeSucceeded && state.Options.Retries > 0 && state.ConsecutiveFailures >= state.Options.Retries {
+		fmt.Printf("🛑 [ORCHESTRATOR] Exceeded %d tolerated consecutive failures - stopping supervisor\n", state.Options.Retries)
+		return fmt.Sprintf("🛑 Supervisor stopped after %d consecutive failures", state.ConsecutiveFailures), nil
+	}
+
+	if !supervisor.ShouldRestart(state.Options.RestartPolicy, probeSucceeded) {
+		fmt.Printf("🏁 [ORCHESTRATOR] RestartPolicy=%s does not restart on this outcome - stopping supervisor\n", state.Options.RestartPolicy)
+		return fmt.Sprintf("🏁 Supervisor stopped (restart=%s, lastProbeSucceeded=%t)", state.Options.RestartPolicy, probeSucceeded), nil
+	}
+
+	fmt.Printf("🔁 [ORCHESTRATOR] Continuing as new for the next tick in %s\n", state.Options.Interval)
+	ctx.ContinueAsNew(state)
+	return nil, nil
+}
+
+// HealthProbeActivity - Simulates a flaky health probe so SupervisorOrchestrator
+// has something to react to.
+func HealthProbeActivity(ctx task.ActivityContext) (any, error) {
+	fmt.Printf("🔄 [ACTIVITY] HealthProbeActivity STARTED\n")
+
+	time.Sleep(100 * time.Millisecond)
+
+	if rand.Intn(4) == 0 {
+		fmt.Printf("❌ [ACTIVITY] HealthProbeActivity FAILED (simulated)\n")
+		return nil, fmt.Errorf("probe target is unreachable (simulated)")
+	}
+
+	timestamp := time.Now().Format("15:04:05")
+	result := fmt.Sprintf("probe target healthy at %s", timestamp)
+	fmt.Printf("✅ [ACTIVITY] HealthProbeActivity SUCCEEDED: %s\n", result)
+	return result, nil
+}